@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+)
+
+// NodeAdapter knows how to collect NodeStats from a single blockchain
+// client's RPC surface. Implementations live in adapter_<chain>.go and
+// register themselves via RegisterAdapter from an init(), so new chains
+// can be added without touching collectStats.
+type NodeAdapter interface {
+	// Chain returns the adapter's chain identifier, e.g. "pocket".
+	Chain() string
+	// CollectNodeStats queries the node and returns a populated NodeStats.
+	CollectNodeStats() (NodeStats, error)
+}
+
+// prober is implemented by adapters that can cheaply check whether their
+// chain is running at cfg.RPCURL, for --chain auto.
+type prober interface {
+	Probe() bool
+}
+
+type adapterFactory func(AdapterConfig) NodeAdapter
+
+var adapterRegistry = map[string]adapterFactory{}
+
+// adapterProbeOrder is the fixed order --chain auto tries registered
+// adapters in. Map iteration order is randomized per run, and some
+// probes aren't mutually exclusive (e.g. a Pocket node also answers
+// Tendermint's /status that CosmosAdapter.Probe checks), so detectChain
+// must not depend on map order to behave deterministically.
+var adapterProbeOrder = []string{"pocket", "cosmos", "evm", "substrate"}
+
+// RegisterAdapter makes a NodeAdapter available under the given chain name.
+func RegisterAdapter(chain string, factory adapterFactory) {
+	adapterRegistry[chain] = factory
+}
+
+func newAdapter(cfg AdapterConfig) (NodeAdapter, error) {
+	factory, ok := adapterRegistry[cfg.Chain]
+	if !ok {
+		return nil, fmt.Errorf("pocketmon: unknown chain %q", cfg.Chain)
+	}
+	return factory(cfg), nil
+}
+
+// detectChain probes registered adapters against cfg.RPCURL, trying
+// adapterProbeOrder first and then any remaining adapters in a stable
+// (sorted) order, and returns the name of the first one that answers.
+func detectChain(cfg AdapterConfig) (string, error) {
+	tried := map[string]bool{}
+
+	for _, chain := range adapterProbeOrder {
+		factory, ok := adapterRegistry[chain]
+		if !ok {
+			continue
+		}
+		tried[chain] = true
+		if probeChain(factory, cfg) {
+			return chain, nil
+		}
+	}
+
+	var rest []string
+	for chain := range adapterRegistry {
+		if !tried[chain] {
+			rest = append(rest, chain)
+		}
+	}
+	sort.Strings(rest)
+
+	for _, chain := range rest {
+		if probeChain(adapterRegistry[chain], cfg) {
+			return chain, nil
+		}
+	}
+
+	return "", fmt.Errorf("pocketmon: could not auto-detect chain (tried %d adapters)", len(tried)+len(rest))
+}
+
+func probeChain(factory adapterFactory, cfg AdapterConfig) bool {
+	a := factory(cfg)
+	p, ok := a.(prober)
+	return ok && p.Probe()
+}
+
+// newAuthedRequest builds an HTTP request and attaches cfg's auth header,
+// if any. Adapters share this so a single config shape covers every chain.
+func newAuthedRequest(method, url string, body io.Reader, cfg AdapterConfig) (*http.Request, error) {
+	req, err := http.NewRequest(method, url, body)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.AuthHeader != "" && cfg.AuthToken != "" {
+		req.Header.Set(cfg.AuthHeader, cfg.AuthToken)
+	}
+	return req, nil
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+func init() {
+	RegisterAdapter("cosmos", func(cfg AdapterConfig) NodeAdapter {
+		return &CosmosAdapter{cfg: withCosmosDefaults(cfg)}
+	})
+}
+
+func withCosmosDefaults(cfg AdapterConfig) AdapterConfig {
+	if cfg.RPCURL == "" {
+		cfg.RPCURL = "http://localhost:26657"
+	}
+	return cfg
+}
+
+// CosmosAdapter talks to a Cosmos SDK / Tendermint node's RPC: /status for
+// sync state and /staking/validators for jailed status.
+type CosmosAdapter struct {
+	cfg AdapterConfig
+}
+
+func (a *CosmosAdapter) Chain() string { return "cosmos" }
+
+func (a *CosmosAdapter) Probe() bool {
+	_, err := a.get(a.cfg.RPCURL + "/status")
+	if err != nil {
+		return false
+	}
+
+	// A Pocket node also answers Tendermint's /status RPC, so /status
+	// alone can't tell a Cosmos SDK chain apart from Pocket. Reject the
+	// probe if Pocket's /v1 API answers on its default local port.
+	if pocketV1Responds() {
+		return false
+	}
+
+	return true
+}
+
+func (a *CosmosAdapter) get(url string) (map[string]interface{}, error) {
+	req, err := newAuthedRequest("GET", url, nil, a.cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Body.Close()
+
+	var result map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (a *CosmosAdapter) CollectNodeStats() (NodeStats, error) {
+	s := NodeStats{
+		Chain: "cosmos",
+	}
+
+	statusResp, err := a.get(a.cfg.RPCURL + "/status")
+	if err != nil {
+		return s, err
+	}
+	result, ok := statusResp["result"].(map[string]interface{})
+	if !ok {
+		return s, errors.New("invalid data from /status call")
+	}
+
+	nodeInfo, ok := result["node_info"].(map[string]interface{})
+	if !ok {
+		return s, errors.New("missing node_info in /status response")
+	}
+	s.AppVersion = fmt.Sprintf("%v", nodeInfo["version"])
+	s.Moniker, _ = nodeInfo["moniker"].(string)
+
+	syncInfo, ok := result["sync_info"].(map[string]interface{})
+	if !ok {
+		return s, errors.New("missing sync_info in /status response")
+	}
+	h, err := strconv.ParseInt(syncInfo["latest_block_height"].(string), 10, 64)
+	if err != nil {
+		return s, err
+	}
+	s.Height = h
+	s.LatestBlockTime, _ = syncInfo["latest_block_time"].(string)
+	s.CatchingUp, _ = syncInfo["catching_up"].(bool)
+
+	if len(a.cfg.Addresses) > 0 {
+		s.Address = a.cfg.Addresses[0]
+
+		valResp, err := a.get(fmt.Sprintf("%s/staking/validators/%s", a.cfg.RPCURL, s.Address))
+		if err == nil {
+			if result, ok := valResp["result"].(map[string]interface{}); ok {
+				s.Jailed, _ = result["jailed"].(bool)
+			}
+		}
+	}
+
+	collectTendermintLivenessStats(a.cfg.RPCURL, a.cfg, &s)
+
+	return s, nil
+}
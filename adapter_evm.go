@@ -0,0 +1,171 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func init() {
+	RegisterAdapter("evm", func(cfg AdapterConfig) NodeAdapter {
+		return &EVMAdapter{cfg: withEVMDefaults(cfg)}
+	})
+}
+
+func withEVMDefaults(cfg AdapterConfig) AdapterConfig {
+	if cfg.RPCURL == "" {
+		cfg.RPCURL = "http://localhost:8545"
+	}
+	return cfg
+}
+
+// EVMAdapter talks to a Geth/EVM node's JSON-RPC endpoint.
+type EVMAdapter struct {
+	cfg                  AdapterConfig
+	nextID               int
+	loggedLivenessCaveat bool
+}
+
+func (a *EVMAdapter) Chain() string { return "evm" }
+
+func (a *EVMAdapter) Probe() bool {
+	_, err := a.call("eth_blockNumber", []interface{}{})
+	return err == nil
+}
+
+func (a *EVMAdapter) call(method string, params []interface{}) (interface{}, error) {
+	a.nextID++
+	reqBody := map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      a.nextID,
+		"method":  method,
+		"params":  params,
+	}
+	data, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := newAuthedRequest("POST", a.cfg.RPCURL, bytes.NewBuffer(data), a.cfg)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	r, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Body.Close()
+
+	var result struct {
+		Result interface{} `json:"result"`
+		Error  *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	if result.Error != nil {
+		return nil, fmt.Errorf("evm rpc %s: %s", method, result.Error.Message)
+	}
+
+	return result.Result, nil
+}
+
+func hexToInt64(h string) (int64, error) {
+	h = strings.TrimPrefix(h, "0x")
+	return strconv.ParseInt(h, 16, 64)
+}
+
+func (a *EVMAdapter) CollectNodeStats() (NodeStats, error) {
+	s := NodeStats{
+		Chain: "evm",
+	}
+
+	blockNumResp, err := a.call("eth_blockNumber", []interface{}{})
+	if err != nil {
+		return s, err
+	}
+	hexHeight, ok := blockNumResp.(string)
+	if !ok {
+		return s, fmt.Errorf("unexpected eth_blockNumber response: %v", blockNumResp)
+	}
+	height, err := hexToInt64(hexHeight)
+	if err != nil {
+		return s, err
+	}
+	s.Height = height
+
+	syncResp, err := a.call("eth_syncing", []interface{}{})
+	if err != nil {
+		return s, err
+	}
+	if syncing, ok := syncResp.(bool); ok {
+		s.CatchingUp = syncing
+	} else {
+		// A non-false result is an object describing sync progress.
+		s.CatchingUp = syncResp != nil
+	}
+
+	if len(a.cfg.Addresses) > 0 {
+		s.Address = a.cfg.Addresses[0]
+		balResp, err := a.call("eth_getBalance", []interface{}{s.Address, "latest"})
+		if err == nil {
+			if hexBal, ok := balResp.(string); ok {
+				if bal, err := hexToInt64(hexBal); err == nil {
+					s.Balance = float64(bal)
+				}
+			}
+		}
+	}
+
+	if peerResp, err := a.call("net_peerCount", []interface{}{}); err == nil {
+		if hexPeers, ok := peerResp.(string); ok {
+			if peers, err := hexToInt64(hexPeers); err == nil {
+				s.PeerCount = int(peers)
+			}
+		}
+	}
+
+	if lag, err := a.blockLagSeconds(); err == nil {
+		s.BlockLagSeconds = lag
+	}
+
+	// MissedBlocksLastN is defined in terms of a validator's block
+	// signatures, which don't exist in EVM's account-based consensus the
+	// same way, and ForkDetected is computed only from Tendermint /block
+	// hashes; leave both at their zero value rather than guessing, and
+	// say so once instead of silently under-reporting.
+	if !a.loggedLivenessCaveat {
+		log.Println("evm adapter: missed-blocks and fork detection are not yet implemented for this chain")
+		a.loggedLivenessCaveat = true
+	}
+
+	return s, nil
+}
+
+func (a *EVMAdapter) blockLagSeconds() (float64, error) {
+	blockResp, err := a.call("eth_getBlockByNumber", []interface{}{"latest", false})
+	if err != nil {
+		return 0, err
+	}
+	block, ok := blockResp.(map[string]interface{})
+	if !ok {
+		return 0, fmt.Errorf("unexpected eth_getBlockByNumber response: %v", blockResp)
+	}
+	hexTimestamp, ok := block["timestamp"].(string)
+	if !ok {
+		return 0, fmt.Errorf("missing timestamp in eth_getBlockByNumber response")
+	}
+	unixSecs, err := hexToInt64(hexTimestamp)
+	if err != nil {
+		return 0, err
+	}
+	return time.Since(time.Unix(unixSecs, 0)).Seconds(), nil
+}
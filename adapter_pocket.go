@@ -0,0 +1,205 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+)
+
+func init() {
+	RegisterAdapter("pocket", func(cfg AdapterConfig) NodeAdapter {
+		return &PocketAdapter{cfg: withPocketDefaults(cfg)}
+	})
+}
+
+// pocketTendermintRPC is the Tendermint RPC Pocket nodes expose
+// alongside their /v1 query API; it isn't configurable via RPCURL
+// since that points at the /v1 port.
+const pocketTendermintRPC = "http://localhost:26657"
+
+func withPocketDefaults(cfg AdapterConfig) AdapterConfig {
+	if cfg.RPCURL == "" {
+		cfg.RPCURL = "http://localhost:8082"
+	}
+	return cfg
+}
+
+// PocketAdapter talks to a Pocket node's /v1/query/* and Tendermint
+// /status RPCs.
+type PocketAdapter struct {
+	cfg AdapterConfig
+}
+
+func (a *PocketAdapter) Chain() string { return "pocket" }
+
+func (a *PocketAdapter) Probe() bool {
+	_, err := a.queryVersion()
+	return err == nil
+}
+
+// pocketV1Responds reports whether a Pocket node's /v1 API answers on
+// its default port. Other adapters use this to rule themselves out when
+// their own RPC probe overlaps with Pocket's shared Tendermint /status.
+func pocketV1Responds() bool {
+	resp, err := client.Get(withPocketDefaults(AdapterConfig{}).RPCURL + "/v1")
+	if err != nil {
+		return false
+	}
+	defer resp.Body.Close()
+	return resp.StatusCode < 400
+}
+
+func (a *PocketAdapter) queryBalance(addr string) (map[string]interface{}, error) {
+	url := a.cfg.RPCURL + "/v1/query/balance"
+
+	s := map[string]interface{}{
+		"address": addr,
+	}
+	data, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", url, bytes.NewBuffer(data))
+	req.Header.Set("Content-Type", "application/json")
+
+	r, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Body.Close()
+
+	var result map[string]interface{}
+	err = json.NewDecoder(r.Body).Decode(&result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (a *PocketAdapter) queryNode(addr string) (map[string]interface{}, error) {
+	url := a.cfg.RPCURL + "/v1/query/node"
+
+	s := map[string]interface{}{
+		"address": addr,
+	}
+	data, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", url, bytes.NewBuffer(data))
+	req.Header.Set("Content-Type", "application/json")
+
+	r, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Body.Close()
+
+	var result map[string]interface{}
+	err = json.NewDecoder(r.Body).Decode(&result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (a *PocketAdapter) queryStatus() (map[string]interface{}, error) {
+	url := pocketTendermintRPC + "/status"
+	r, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+
+	defer r.Body.Close()
+
+	var result map[string]interface{}
+	err = json.NewDecoder(r.Body).Decode(&result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (a *PocketAdapter) queryVersion() (string, error) {
+	url := a.cfg.RPCURL + "/v1"
+	r, err := client.Get(url)
+	if err != nil {
+		return "", err
+	}
+
+	defer r.Body.Close()
+
+	var result string
+	err = json.NewDecoder(r.Body).Decode(&result)
+	if err != nil {
+		return "", err
+	}
+
+	return result, nil
+}
+
+func (a *PocketAdapter) CollectNodeStats() (NodeStats, error) {
+	s := NodeStats{
+		Chain: "pocket",
+	}
+
+	//Get version
+	ver, err := a.queryVersion()
+	if err != nil {
+		return s, err
+	}
+	s.AppVersion = ver
+
+	statusResp, err := a.queryStatus()
+	if err != nil {
+		return s, err
+	}
+	status, ok := statusResp["result"].(map[string]interface{})
+	if !ok {
+		log.Println(statusResp)
+		return s, errors.New("Invalid data from /status call")
+	}
+
+	nodeInfo := status["node_info"].(map[string]interface{})
+	s.Address = nodeInfo["id"].(string)
+	s.Moniker = nodeInfo["moniker"].(string)
+	syncInfo := status["sync_info"].(map[string]interface{})
+	h, err := strconv.ParseInt(syncInfo["latest_block_height"].(string), 10, 64)
+	if err != nil {
+		return s, err
+	}
+	s.Height = h
+	s.LatestBlockTime = syncInfo["latest_block_time"].(string)
+	s.CatchingUp = syncInfo["catching_up"].(bool)
+
+	if len(a.cfg.Addresses) > 0 {
+		s.Address = a.cfg.Addresses[0]
+	}
+
+	nodeResp, err := a.queryNode(s.Address)
+	if err != nil {
+		return s, err
+	}
+	// s.Chains = nodeResp["chains"].([]string)
+	s.PublicKey = nodeResp["public_key"].(string)
+	s.Jailed = nodeResp["jailed"].(bool)
+	s.ServiceUrl = nodeResp["service_url"].(string)
+
+	balResp, err := a.queryBalance(s.Address)
+	if err != nil {
+		return s, err
+	}
+	s.Balance = balResp["balance"].(float64)
+
+	collectTendermintLivenessStats(pocketTendermintRPC, a.cfg, &s)
+
+	return s, nil
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+)
+
+func init() {
+	RegisterAdapter("substrate", func(cfg AdapterConfig) NodeAdapter {
+		return &SubstrateAdapter{cfg: withSubstrateDefaults(cfg)}
+	})
+}
+
+func withSubstrateDefaults(cfg AdapterConfig) AdapterConfig {
+	if cfg.RPCURL == "" {
+		cfg.RPCURL = "http://localhost:9933"
+	}
+	return cfg
+}
+
+// SubstrateAdapter talks to a Polkadot/Substrate node's JSON-RPC endpoint.
+type SubstrateAdapter struct {
+	cfg                  AdapterConfig
+	nextID               int
+	loggedLivenessCaveat bool
+}
+
+func (a *SubstrateAdapter) Chain() string { return "substrate" }
+
+func (a *SubstrateAdapter) Probe() bool {
+	_, err := a.call("system_health", []interface{}{})
+	return err == nil
+}
+
+func (a *SubstrateAdapter) call(method string, params []interface{}) (json.RawMessage, error) {
+	a.nextID++
+	reqBody := map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      a.nextID,
+		"method":  method,
+		"params":  params,
+	}
+	data, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := newAuthedRequest("POST", a.cfg.RPCURL, bytes.NewBuffer(data), a.cfg)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	r, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Body.Close()
+
+	var result struct {
+		Result json.RawMessage `json:"result"`
+		Error  *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	if result.Error != nil {
+		return nil, fmt.Errorf("substrate rpc %s: %s", method, result.Error.Message)
+	}
+
+	return result.Result, nil
+}
+
+func (a *SubstrateAdapter) CollectNodeStats() (NodeStats, error) {
+	s := NodeStats{
+		Chain: "substrate",
+	}
+
+	healthRaw, err := a.call("system_health", []interface{}{})
+	if err != nil {
+		return s, err
+	}
+	var health struct {
+		Peers     int  `json:"peers"`
+		IsSyncing bool `json:"isSyncing"`
+	}
+	if err := json.Unmarshal(healthRaw, &health); err != nil {
+		return s, err
+	}
+	s.CatchingUp = health.IsSyncing
+	s.PeerCount = health.Peers
+
+	headerRaw, err := a.call("chain_getHeader", []interface{}{})
+	if err != nil {
+		return s, err
+	}
+	var header struct {
+		Number string `json:"number"`
+	}
+	if err := json.Unmarshal(headerRaw, &header); err != nil {
+		return s, err
+	}
+	height, err := hexToInt64(header.Number)
+	if err != nil {
+		return s, err
+	}
+	s.Height = height
+
+	// BlockLagSeconds, MissedBlocksLastN, and ForkDetected rely on
+	// Tendermint-specific RPCs (/status block time, /block signatures and
+	// hashes on local and reference nodes)
+	// that Substrate doesn't expose the same way; leave them at their
+	// zero value rather than guessing, and say so once instead of
+	// silently under-reporting.
+	if !a.loggedLivenessCaveat {
+		log.Println("substrate adapter: block lag, missed blocks, and fork detection are not yet implemented for this chain")
+		a.loggedLivenessCaveat = true
+	}
+
+	return s, nil
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// fakeProbeAdapter is a minimal NodeAdapter+prober for pinning
+// detectChain's ordering in tests without hitting real adapter RPCs.
+type fakeProbeAdapter struct {
+	chain   string
+	probeOK bool
+}
+
+func (a *fakeProbeAdapter) Chain() string                        { return a.chain }
+func (a *fakeProbeAdapter) CollectNodeStats() (NodeStats, error) { return NodeStats{}, nil }
+func (a *fakeProbeAdapter) Probe() bool                          { return a.probeOK }
+
+func withTestRegistry(t *testing.T, registry map[string]adapterFactory) {
+	t.Helper()
+	orig := adapterRegistry
+	adapterRegistry = registry
+	t.Cleanup(func() { adapterRegistry = orig })
+}
+
+func TestDetectChain_PrefersEarlierAdapterProbeOrderOnOverlap(t *testing.T) {
+	// Two adapters both probe true, mirroring Pocket and Cosmos both
+	// answering Tendermint's /status. adapterProbeOrder puts "pocket"
+	// ahead of "cosmos", so detectChain must return "pocket" every time,
+	// not whichever order map iteration happens to surface.
+	withTestRegistry(t, map[string]adapterFactory{
+		"cosmos": func(cfg AdapterConfig) NodeAdapter { return &fakeProbeAdapter{chain: "cosmos", probeOK: true} },
+		"pocket": func(cfg AdapterConfig) NodeAdapter { return &fakeProbeAdapter{chain: "pocket", probeOK: true} },
+	})
+
+	for i := 0; i < 20; i++ {
+		chain, err := detectChain(AdapterConfig{})
+		if err != nil {
+			t.Fatalf("detectChain returned error: %v", err)
+		}
+		if chain != "pocket" {
+			t.Fatalf("expected pocket to win the overlap every time, got %q", chain)
+		}
+	}
+}
+
+func TestDetectChain_FallsBackToSortedOrderForUnlistedAdapters(t *testing.T) {
+	withTestRegistry(t, map[string]adapterFactory{
+		"zzz-chain": func(cfg AdapterConfig) NodeAdapter { return &fakeProbeAdapter{chain: "zzz-chain", probeOK: true} },
+		"aaa-chain": func(cfg AdapterConfig) NodeAdapter { return &fakeProbeAdapter{chain: "aaa-chain", probeOK: true} },
+	})
+
+	chain, err := detectChain(AdapterConfig{})
+	if err != nil {
+		t.Fatalf("detectChain returned error: %v", err)
+	}
+	if chain != "aaa-chain" {
+		t.Fatalf("expected the alphabetically-first unlisted adapter to win, got %q", chain)
+	}
+}
+
+func TestDetectChain_NoneProbingIsAnError(t *testing.T) {
+	withTestRegistry(t, map[string]adapterFactory{
+		"pocket": func(cfg AdapterConfig) NodeAdapter { return &fakeProbeAdapter{chain: "pocket", probeOK: false} },
+	})
+
+	if _, err := detectChain(AdapterConfig{}); err == nil {
+		t.Fatalf("expected an error when no adapter probes successfully")
+	}
+}
+
+func TestCosmosAdapterProbe_RejectsOverlapWithPocket(t *testing.T) {
+	status := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"result":{}}`))
+	}))
+	defer status.Close()
+
+	// pocketV1Responds always probes Pocket's hardcoded default port, so
+	// pin a listener there to simulate a Pocket node also answering.
+	ln, err := net.Listen("tcp", "localhost:8082")
+	if err != nil {
+		t.Skipf("port 8082 unavailable in this environment: %v", err)
+	}
+	pocket := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})}
+	go pocket.Serve(ln)
+	defer pocket.Close()
+
+	a := &CosmosAdapter{cfg: AdapterConfig{RPCURL: status.URL}}
+	if a.Probe() {
+		t.Fatalf("expected CosmosAdapter.Probe to reject a host that also answers Pocket's /v1")
+	}
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"time"
+)
+
+const defaultCooldown = 15 * time.Minute
+
+// AlertRule is one declarative condition evaluated against each tick's
+// Stats. Kind selects which field(s) it looks at:
+//
+//	jailed               - node.jailed == true
+//	catching_up_for      - node.catching_up, true continuously for >= For
+//	disk_free_pct_below  - host disk free percentage < Threshold
+//	height_stalled_for   - node.height unchanged across ticks for >= For
+//	balance_below        - node.balance < Threshold
+type AlertRule struct {
+	Name      string  `json:"name"`
+	Kind      string  `json:"kind"`
+	Threshold float64 `json:"threshold,omitempty"`
+	For       string  `json:"for,omitempty"`      // Go duration, e.g. "10m"
+	Cooldown  string  `json:"cooldown,omitempty"` // Go duration, defaults to 15m
+}
+
+type ruleState struct {
+	conditionSince time.Time
+	lastFired      time.Time
+	haveHeight     bool
+	lastHeight     int64
+}
+
+// AlertEngine evaluates a fixed set of AlertRules on every tick and
+// dispatches through its Notifiers, deduplicating with a per-rule
+// cooldown so a standing condition doesn't notify on every tick.
+type AlertEngine struct {
+	rules     []AlertRule
+	notifiers []Notifier
+	state     map[string]*ruleState
+}
+
+func NewAlertEngine(rules []AlertRule, notifiers []Notifier) *AlertEngine {
+	return &AlertEngine{
+		rules:     rules,
+		notifiers: notifiers,
+		state:     map[string]*ruleState{},
+	}
+}
+
+func (e *AlertEngine) Evaluate(s Stats) {
+	now := time.Now()
+	for _, r := range e.rules {
+		st, ok := e.state[r.Name]
+		if !ok {
+			st = &ruleState{}
+			e.state[r.Name] = st
+		}
+
+		cond, msg := e.evalCondition(r, s, st)
+		if !cond {
+			st.conditionSince = time.Time{}
+			continue
+		}
+		if st.conditionSince.IsZero() {
+			st.conditionSince = now
+		}
+
+		forDur, _ := time.ParseDuration(r.For)
+		if now.Sub(st.conditionSince) < forDur {
+			continue
+		}
+
+		cooldown, err := time.ParseDuration(r.Cooldown)
+		if err != nil || cooldown == 0 {
+			cooldown = defaultCooldown
+		}
+		if !st.lastFired.IsZero() && now.Sub(st.lastFired) < cooldown {
+			continue
+		}
+
+		st.lastFired = now
+		e.fire(r, msg)
+	}
+}
+
+func (e *AlertEngine) evalCondition(r AlertRule, s Stats, st *ruleState) (bool, string) {
+	switch r.Kind {
+	case "jailed":
+		return s.Node.Jailed, "node is jailed"
+	case "catching_up_for":
+		return s.Node.CatchingUp, "node is still catching up"
+	case "disk_free_pct_below":
+		pct := 100 * float64(s.Host.DiskFree) / float64(s.Host.DiskTotal)
+		return pct < r.Threshold, fmt.Sprintf("disk free %.1f%% is below %.1f%%", pct, r.Threshold)
+	case "height_stalled_for":
+		stalled := st.haveHeight && s.Node.Height == st.lastHeight
+		st.lastHeight = s.Node.Height
+		st.haveHeight = true
+		return stalled, fmt.Sprintf("height stuck at %d", s.Node.Height)
+	case "balance_below":
+		return s.Node.Balance < r.Threshold, fmt.Sprintf("balance %.2f is below %.2f", s.Node.Balance, r.Threshold)
+	default:
+		log.Printf("alerts: unknown rule kind %q for rule %q", r.Kind, r.Name)
+		return false, ""
+	}
+}
+
+func (e *AlertEngine) fire(r AlertRule, msg string) {
+	alert := Alert{Rule: r.Name, Message: msg}
+	log.Printf("ALERT [%s] %s", r.Name, msg)
+
+	for _, n := range e.notifiers {
+		if err := n.Notify(alert); err != nil {
+			log.Println("Err notifying:", err)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+type fakeNotifier struct {
+	alerts []Alert
+}
+
+func (n *fakeNotifier) Notify(a Alert) error {
+	n.alerts = append(n.alerts, a)
+	return nil
+}
+
+func TestAlertEngineEvaluate_CooldownDedupesRepeatedFires(t *testing.T) {
+	notifier := &fakeNotifier{}
+	rule := AlertRule{Name: "jailed", Kind: "jailed", Cooldown: "20ms"}
+	engine := NewAlertEngine([]AlertRule{rule}, []Notifier{notifier})
+
+	stats := Stats{Node: NodeStats{Jailed: true}}
+
+	engine.Evaluate(stats)
+	if len(notifier.alerts) != 1 {
+		t.Fatalf("expected 1 alert after first evaluate, got %d", len(notifier.alerts))
+	}
+
+	engine.Evaluate(stats)
+	if len(notifier.alerts) != 1 {
+		t.Fatalf("expected cooldown to suppress the second fire, got %d alerts", len(notifier.alerts))
+	}
+
+	time.Sleep(25 * time.Millisecond)
+	engine.Evaluate(stats)
+	if len(notifier.alerts) != 2 {
+		t.Fatalf("expected a fire once the cooldown elapsed, got %d alerts", len(notifier.alerts))
+	}
+}
+
+func TestAlertEngineEvaluate_CooldownSurvivesConditionClearing(t *testing.T) {
+	notifier := &fakeNotifier{}
+	rule := AlertRule{Name: "jailed", Kind: "jailed", Cooldown: "1h"}
+	engine := NewAlertEngine([]AlertRule{rule}, []Notifier{notifier})
+
+	engine.Evaluate(Stats{Node: NodeStats{Jailed: true}})
+	if len(notifier.alerts) != 1 {
+		t.Fatalf("expected 1 alert, got %d", len(notifier.alerts))
+	}
+
+	// Condition clears, then re-trips; clearing only resets conditionSince,
+	// not lastFired, so the 1h cooldown still applies to the re-trip.
+	engine.Evaluate(Stats{Node: NodeStats{Jailed: false}})
+	engine.Evaluate(Stats{Node: NodeStats{Jailed: true}})
+	if len(notifier.alerts) != 1 {
+		t.Fatalf("expected cooldown to still suppress the refired alert, got %d", len(notifier.alerts))
+	}
+}
+
+func TestAlertEngineEvaluate_ForDurationDelaysFirstFire(t *testing.T) {
+	notifier := &fakeNotifier{}
+	rule := AlertRule{Name: "stalled", Kind: "height_stalled_for", For: "30ms", Cooldown: "1h"}
+	engine := NewAlertEngine([]AlertRule{rule}, []Notifier{notifier})
+
+	stats := Stats{Node: NodeStats{Height: 100}}
+
+	engine.Evaluate(stats) // establishes the baseline height, can't be "stalled" yet
+	if len(notifier.alerts) != 0 {
+		t.Fatalf("expected no alert on the first tick, got %d", len(notifier.alerts))
+	}
+
+	engine.Evaluate(stats) // height unchanged: condition trips, but For hasn't elapsed
+	if len(notifier.alerts) != 0 {
+		t.Fatalf("expected no alert before the For duration elapses, got %d", len(notifier.alerts))
+	}
+
+	time.Sleep(40 * time.Millisecond)
+	engine.Evaluate(stats)
+	if len(notifier.alerts) != 1 {
+		t.Fatalf("expected an alert once the For duration elapsed, got %d", len(notifier.alerts))
+	}
+}
+
+func TestAlertEngineEvaluate_UnknownKindDoesNotFire(t *testing.T) {
+	notifier := &fakeNotifier{}
+	rule := AlertRule{Name: "bogus", Kind: "not_a_real_kind"}
+	engine := NewAlertEngine([]AlertRule{rule}, []Notifier{notifier})
+
+	engine.Evaluate(Stats{})
+	if len(notifier.alerts) != 0 {
+		t.Fatalf("expected no alert for an unknown rule kind, got %d", len(notifier.alerts))
+	}
+}
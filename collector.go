@@ -4,7 +4,6 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"io/ioutil"
 	"log"
@@ -25,22 +24,38 @@ const (
 )
 
 var (
-	client = &http.Client{}
+	client = newHTTPClient()
+
+	// activeAdapter is the NodeAdapter selected (or auto-detected) at
+	// startup. It's set once in start() before the collection loop begins.
+	activeAdapter NodeAdapter
+
+	// activeAlerts is the AlertEngine built from the config file, if any.
+	// It's nil when no alert rules are configured.
+	activeAlerts *AlertEngine
+
+	// activeSpool buffers Stats payloads that failed to deliver so the
+	// background flusher (see retry.go) can retry them later.
+	activeSpool *Spool
 )
 
 type NodeStats struct {
-	Chain           string   `json:"chain"`
-	AppVersion      string   `json:"app_version"`
-	Moniker         string   `json:"moniker"`
-	Height          int64    `json:"height"`
-	LatestBlockTime string   `json:latest_block_time`
-	CatchingUp      bool     `json:"catching_up"`
-	Balance         float64  `json:"balance"`
-	Chains          []string `json:"chains"`
-	Jailed          bool     `json:"jailed"`
-	ServiceUrl      string   `json:"service_url"`
-	Address         string   `json:"address"`
-	PublicKey       string   `json:"public_key"`
+	Chain             string   `json:"chain"`
+	AppVersion        string   `json:"app_version"`
+	Moniker           string   `json:"moniker"`
+	Height            int64    `json:"height"`
+	LatestBlockTime   string   `json:latest_block_time`
+	CatchingUp        bool     `json:"catching_up"`
+	Balance           float64  `json:"balance"`
+	Chains            []string `json:"chains"`
+	Jailed            bool     `json:"jailed"`
+	ServiceUrl        string   `json:"service_url"`
+	Address           string   `json:"address"`
+	PublicKey         string   `json:"public_key"`
+	PeerCount         int      `json:"peer_count"`
+	BlockLagSeconds   float64  `json:"block_lag_seconds"`
+	MissedBlocksLastN int      `json:"missed_blocks_last_n"`
+	ForkDetected      bool     `json:"fork_detected"`
 }
 
 func (s NodeStats) String() string {
@@ -95,7 +110,7 @@ func getUrl(node string) string {
 	return fmt.Sprintf("%s/%s", endpoint, node)
 }
 
-func sendStats(node string, key string, s Stats) error {
+func sendStats(ctx context.Context, node string, key string, s Stats) error {
 	url := getUrl(node)
 
 	data, err := json.Marshal(s)
@@ -103,166 +118,51 @@ func sendStats(node string, key string, s Stats) error {
 		return err
 	}
 
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(data))
-	req.Header.Set("x-api-key", key)
-	req.Header.Set("Content-Type", "application/json")
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
 
-	resp, err := client.Do(req)
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(data))
 	if err != nil {
 		return err
 	}
-
-	if resp.StatusCode > 399 {
-		defer resp.Body.Close()
-		body, _ := ioutil.ReadAll(resp.Body)
-		return errors.New(fmt.Sprintf("%d %s\n", resp.StatusCode, string(body)))
-	}
-	return nil
-}
-
-func queryBalance(addr string) (map[string]interface{}, error) {
-	url := "http://localhost:8082/v1/query/balance"
-
-	s := map[string]interface{}{
-		"address": addr,
-	}
-	data, err := json.Marshal(s)
-	if err != nil {
-		//
-		return nil, err
-	}
-
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(data))
-	req.Header.Set("Content-Type", "application/json")
-
-	r, err := client.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer r.Body.Close()
-
-	var result map[string]interface{}
-	err = json.NewDecoder(r.Body).Decode(&result)
-	if err != nil {
-		return nil, err
-	}
-
-	return result, nil
-}
-func queryNode(addr string) (map[string]interface{}, error) {
-	url := "http://localhost:8082/v1/query/node"
-
-	s := map[string]interface{}{
-		"address": addr,
-	}
-	data, err := json.Marshal(s)
-	if err != nil {
-		return nil, err
-	}
-
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(data))
+	req.Header.Set("x-api-key", key)
 	req.Header.Set("Content-Type", "application/json")
 
-	r, err := client.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer r.Body.Close()
-
-	var result map[string]interface{}
-	err = json.NewDecoder(r.Body).Decode(&result)
-	if err != nil {
-		return nil, err
-	}
-
-	return result, nil
-}
-
-func queryStatus() (map[string]interface{}, error) {
-	url := "http://localhost:26657/status"
-	r, err := client.Get(url)
-	if err != nil {
-		return nil, err
-	}
-
-	defer r.Body.Close()
-
-	var result map[string]interface{}
-	err = json.NewDecoder(r.Body).Decode(&result)
+	resp, err := client.Do(req)
 	if err != nil {
-		return nil, err
+		return &retryableError{err: err}
 	}
+	defer resp.Body.Close()
 
-	return result, nil
-}
-func queryVersion() (string, error) {
-	url := "http://localhost:8082/v1"
-	r, err := client.Get(url)
-	if err != nil {
-		return "", err
+	if resp.StatusCode == http.StatusTooManyRequests {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return &retryableError{
+			err:        fmt.Errorf("%d %s", resp.StatusCode, string(body)),
+			retryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+		}
 	}
 
-	defer r.Body.Close()
-
-	var result string
-	err = json.NewDecoder(r.Body).Decode(&result)
-	if err != nil {
-		return "", err
+	if resp.StatusCode > 399 {
+		body, _ := ioutil.ReadAll(resp.Body)
+		if resp.StatusCode >= 500 {
+			return &retryableError{err: fmt.Errorf("%d %s", resp.StatusCode, string(body))}
+		}
+		return fmt.Errorf("%d %s", resp.StatusCode, string(body))
 	}
-
-	return result, nil
+	return nil
 }
 
-func collectNodeStats() (NodeStats, error) {
-	s := NodeStats{
-		Chain: "pocket",
-	}
-
-	//Get version
-	ver, err := queryVersion()
-	if err != nil {
-		return s, err
-	}
-	s.AppVersion = ver
-
-	statusResp, err := queryStatus()
-	if err != nil {
-		return s, err
-	}
-	status, ok := statusResp["result"].(map[string]interface{})
-	if !ok {
-		log.Println(statusResp)
-		return s, errors.New("Invalid data from /status call")
+// parseRetryAfter understands the delta-seconds form of Retry-After; it
+// returns 0 (meaning "use normal backoff") for anything else, including
+// the less common HTTP-date form.
+func parseRetryAfter(h string) time.Duration {
+	if h == "" {
+		return 0
 	}
-
-	nodeInfo := status["node_info"].(map[string]interface{})
-	s.Address = nodeInfo["id"].(string)
-	s.Moniker = nodeInfo["moniker"].(string)
-	syncInfo := status["sync_info"].(map[string]interface{})
-	h, err := strconv.ParseInt(syncInfo["latest_block_height"].(string), 10, 64)
-	if err != nil {
-		return s, err
+	if secs, err := strconv.Atoi(h); err == nil {
+		return time.Duration(secs) * time.Second
 	}
-	s.Height = h
-	s.LatestBlockTime = syncInfo["latest_block_time"].(string)
-	s.CatchingUp = syncInfo["catching_up"].(bool)
-
-	nodeResp, err := queryNode(s.Address)
-	if err != nil {
-		return s, err
-	}
-	// s.Chains = nodeResp["chains"].([]string)
-	s.PublicKey = nodeResp["public_key"].(string)
-	s.Jailed = nodeResp["jailed"].(bool)
-	s.ServiceUrl = nodeResp["service_url"].(string)
-
-	balResp, err := queryBalance(s.Address)
-	if err != nil {
-		return s, err
-	}
-	s.Balance = balResp["balance"].(float64)
-
-	return s, nil
+	return 0
 }
 
 func collectHostStats() (HostStats, error) {
@@ -313,7 +213,7 @@ func collectStats() (Stats, error) {
 		log.Println("err host stats:")
 		return s, err
 	}
-	ns, err := collectNodeStats()
+	ns, err := activeAdapter.CollectNodeStats()
 	if err != nil {
 		log.Println("err node stats:")
 		return s, err
@@ -323,36 +223,104 @@ func collectStats() (Stats, error) {
 	s.Node = ns
 
 	log.Printf("%s", s.String())
+	updateMetrics(s)
+
+	if activeAlerts != nil {
+		activeAlerts.Evaluate(s)
+	}
 
 	return s, nil
 }
 
-func collectAndSend(node, key string) {
+func collectAndSend(ctx context.Context, node, key string, alertsOnly bool) {
 	stats, err := collectStats()
 	if err != nil {
 		log.Println("Err collecting stats:", err)
 		return
 	}
 
-	err = sendStats(node, key, stats)
+	if alertsOnly {
+		return
+	}
+
+	err = sendStats(ctx, node, key, stats)
 	if err != nil {
 		log.Println("Err sending stats:", err)
+		if activeSpool != nil && isRetryable(err) {
+			if serr := activeSpool.Enqueue(stats); serr != nil {
+				log.Println("Err spooling stats:", serr)
+			}
+		}
 		return
 	}
 }
 
-func start(ctx context.Context, node, key string, daemon bool) {
-	fmt.Printf(`Started monitoring node: %s
+func start(ctx context.Context, node, key, chain, configPath, serveAddr, spoolDir string, alertsOnly, daemon bool) {
+	if serveAddr != "" {
+		serveMetrics(serveAddr)
+	}
+
+	if spoolDir != "" {
+		sp, err := NewSpool(spoolDir, 10000)
+		if err != nil {
+			log.Fatalf("Err creating spool dir %s: %s", spoolDir, err)
+		}
+		activeSpool = sp
+		go runFlusher(ctx, activeSpool, node, key)
+	}
+
+	var cfg Config
+	if configPath != "" {
+		var err error
+		cfg, err = loadConfig(configPath)
+		if err != nil {
+			log.Fatalf("Err loading config %s: %s", configPath, err)
+		}
+	}
+
+	if len(cfg.Alerts) > 0 {
+		notifiers := make([]Notifier, 0, len(cfg.Notifiers))
+		for _, nc := range cfg.Notifiers {
+			n, err := newNotifier(nc)
+			if err != nil {
+				log.Fatalf("Err building notifier: %s", err)
+			}
+			notifiers = append(notifiers, n)
+		}
+		activeAlerts = NewAlertEngine(cfg.Alerts, notifiers)
+	}
+
+	adapterCfg := cfg.adapterConfigFor(chain)
+	if chain == "" || chain == "auto" {
+		if cfg.Chain != "" {
+			chain = cfg.Chain
+		} else {
+			detected, err := detectChain(adapterCfg)
+			if err != nil {
+				log.Fatalf("Err detecting chain: %s", err)
+			}
+			chain = detected
+		}
+		adapterCfg = cfg.adapterConfigFor(chain)
+	}
+
+	adapter, err := newAdapter(adapterCfg)
+	if err != nil {
+		log.Fatalf("Err building adapter: %s", err)
+	}
+	activeAdapter = adapter
+
+	fmt.Printf(`Started monitoring %s node: %s
 You can view health status at https://lunar.dev/app
-`, node)
+`, adapter.Chain(), node)
 	ticker := time.NewTicker(sleepTime)
-	collectAndSend()
+	collectAndSend(ctx, node, key, alertsOnly)
 	for {
 		select {
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
-			collectAndSend(node, key)
+			collectAndSend(ctx, node, key, alertsOnly)
 		}
 	}
 }
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+)
+
+// AdapterConfig holds the per-chain settings a NodeAdapter needs to talk to
+// a node: where it lives, how to authenticate, and which addresses to watch.
+type AdapterConfig struct {
+	Chain      string   `json:"chain"`
+	RPCURL     string   `json:"rpc_url"`
+	AuthHeader string   `json:"auth_header,omitempty"`
+	AuthToken  string   `json:"auth_token,omitempty"`
+	Addresses  []string `json:"addresses,omitempty"`
+
+	// MissedBlocksWindow is how many recent blocks to walk when counting
+	// missed signatures (N); it defaults to defaultMissedBlocksWindow.
+	MissedBlocksWindow int `json:"missed_blocks_window,omitempty"`
+	// ForkReferenceRPCs are other nodes' Tendermint RPC URLs to
+	// cross-check block hashes against for fork detection.
+	ForkReferenceRPCs []string `json:"fork_reference_rpcs,omitempty"`
+}
+
+// Config is the top-level pocketmon config file, loaded with --config.
+type Config struct {
+	// Chain is the fallback chain used when --chain is left at its
+	// default ("auto"), so a config file can pin the chain without the
+	// user having to pass --chain on every run. An explicit --chain
+	// value still wins over this.
+	Chain     string           `json:"chain"`
+	Adapters  []AdapterConfig  `json:"adapters"`
+	Alerts    []AlertRule      `json:"alerts,omitempty"`
+	Notifiers []NotifierConfig `json:"notifiers,omitempty"`
+}
+
+func loadConfig(path string) (Config, error) {
+	var c Config
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return c, err
+	}
+
+	if err := json.Unmarshal(data, &c); err != nil {
+		return c, err
+	}
+
+	return c, nil
+}
+
+// adapterConfigFor returns the AdapterConfig for chain, or a zero-value
+// config with just the chain set if none was given in the file.
+func (c Config) adapterConfigFor(chain string) AdapterConfig {
+	for _, a := range c.Adapters {
+		if a.Chain == chain {
+			return a
+		}
+	}
+	return AdapterConfig{Chain: chain}
+}
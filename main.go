@@ -13,6 +13,11 @@ var (
 	nodeId      string
 	apiKey      string
 	runAsDaemon bool
+	chain       string
+	configPath  string
+	serveAddr   string
+	alertsOnly  bool
+	spoolDir    string
 )
 
 func waitForInterrupt() {
@@ -29,7 +34,7 @@ func main() {
 notifies you when there is an issue`,
 		Run: func(cmd *cobra.Command, args []string) {
 			ctx, cancel := context.WithCancel(context.Background())
-			go start(ctx, nodeId, apiKey, runAsDaemon)
+			go start(ctx, nodeId, apiKey, chain, configPath, serveAddr, spoolDir, alertsOnly, runAsDaemon)
 			waitForInterrupt()
 			cancel()
 		},
@@ -39,6 +44,11 @@ notifies you when there is an issue`,
 	rootCmd.MarkFlagRequired("node")
 	rootCmd.Flags().StringVarP(&apiKey, "key", "k", "", "API Key (required) (Get from lunar.dev)")
 	rootCmd.MarkFlagRequired("key")
+	rootCmd.Flags().StringVarP(&chain, "chain", "c", "auto", "Chain to monitor: pocket|cosmos|evm|substrate|auto")
+	rootCmd.Flags().StringVar(&configPath, "config", "", "Path to config file (JSON): adapter settings, alert rules, and notifiers")
+	rootCmd.Flags().StringVar(&serveAddr, "serve", "", "Address to serve Prometheus /metrics on, e.g. :9100 (disabled by default)")
+	rootCmd.Flags().BoolVar(&alertsOnly, "alerts-only", false, "Evaluate alert rules without pushing stats to injest.lunar.dev")
+	rootCmd.Flags().StringVar(&spoolDir, "spool-dir", "", "Directory to buffer undelivered stats in for retry (disabled by default)")
 	//@Todo
 	// rootCmd.Flags().BoolVarP(&runAsDaemon, "daemon", "d", false, "Run in background as daemon")
 
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+var (
+	metricNodeHeight = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "pocketmon_node_height",
+		Help: "Latest block height reported by the monitored node.",
+	})
+	metricNodeCatchingUp = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "pocketmon_node_catching_up",
+		Help: "1 if the node is still catching up to the chain tip, 0 otherwise.",
+	})
+	metricNodeJailed = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "pocketmon_node_jailed",
+		Help: "1 if the node's validator is jailed, 0 otherwise.",
+	})
+	metricNodeBalance = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "pocketmon_balance",
+		Help: "Account balance of the monitored node's address.",
+	})
+	metricHostMemFreeBytes = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "pocketmon_host_mem_free_bytes",
+		Help: "Free host memory, in bytes.",
+	})
+	metricHostCPUPercent = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "pocketmon_host_cpu_percent",
+		Help: "Host CPU usage percentage.",
+	})
+	metricNodePeerCount = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "pocketmon_node_peer_count",
+		Help: "Number of peers the node is connected to.",
+	})
+	metricNodeBlockLagSeconds = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "pocketmon_node_block_lag_seconds",
+		Help: "Seconds between the node's latest block time and wall clock.",
+	})
+	metricNodeMissedBlocks = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "pocketmon_node_missed_blocks_last_n",
+		Help: "Blocks missed by the validator's address in the last N blocks.",
+	})
+	metricNodeForkDetected = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "pocketmon_node_fork_detected",
+		Help: "1 if the local block hash diverged from a reference RPC, 0 otherwise.",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(
+		metricNodeHeight,
+		metricNodeCatchingUp,
+		metricNodeJailed,
+		metricNodeBalance,
+		metricHostMemFreeBytes,
+		metricHostCPUPercent,
+		metricNodePeerCount,
+		metricNodeBlockLagSeconds,
+		metricNodeMissedBlocks,
+		metricNodeForkDetected,
+	)
+}
+
+func boolToFloat64(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// updateMetrics copies the latest collected Stats into the Prometheus
+// gauges so a scrape always reflects the most recent tick.
+func updateMetrics(s Stats) {
+	metricNodeHeight.Set(float64(s.Node.Height))
+	metricNodeCatchingUp.Set(boolToFloat64(s.Node.CatchingUp))
+	metricNodeJailed.Set(boolToFloat64(s.Node.Jailed))
+	metricNodeBalance.Set(s.Node.Balance)
+	metricHostMemFreeBytes.Set(float64(s.Host.MemoryFree))
+	metricHostCPUPercent.Set(s.Host.CPUUsagePercent)
+	metricNodePeerCount.Set(float64(s.Node.PeerCount))
+	metricNodeBlockLagSeconds.Set(s.Node.BlockLagSeconds)
+	metricNodeMissedBlocks.Set(float64(s.Node.MissedBlocksLastN))
+	metricNodeForkDetected.Set(boolToFloat64(s.Node.ForkDetected))
+}
+
+// serveMetrics exposes /metrics in Prometheus text format on addr, e.g.
+// ":9100". It runs in the background; a failure to bind is fatal since
+// it means the operator's scrape config will never succeed.
+func serveMetrics(addr string) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+
+	log.Printf("Serving Prometheus metrics on %s/metrics", addr)
+	go func() {
+		if err := http.ListenAndServe(addr, mux); err != nil {
+			log.Fatalf("Err serving metrics: %s", err)
+		}
+	}()
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/smtp"
+)
+
+// Alert is a single rule firing, handed to every configured Notifier.
+type Alert struct {
+	Rule    string
+	Message string
+}
+
+// Notifier delivers an Alert somewhere an operator will see it.
+type Notifier interface {
+	Notify(a Alert) error
+}
+
+// NotifierConfig configures one Notifier. Type selects which fields apply.
+type NotifierConfig struct {
+	Type string `json:"type"` // slack|discord|pagerduty|webhook|smtp
+
+	// slack, discord, webhook
+	URL string `json:"url,omitempty"`
+
+	// pagerduty
+	RoutingKey string `json:"routing_key,omitempty"`
+
+	// smtp
+	SMTPHost string `json:"smtp_host,omitempty"`
+	SMTPPort int    `json:"smtp_port,omitempty"`
+	From     string `json:"from,omitempty"`
+	To       string `json:"to,omitempty"`
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+}
+
+func newNotifier(cfg NotifierConfig) (Notifier, error) {
+	switch cfg.Type {
+	case "slack":
+		return &SlackNotifier{webhookURL: cfg.URL}, nil
+	case "discord":
+		return &DiscordNotifier{webhookURL: cfg.URL}, nil
+	case "pagerduty":
+		return &PagerDutyNotifier{routingKey: cfg.RoutingKey}, nil
+	case "webhook":
+		return &WebhookNotifier{url: cfg.URL}, nil
+	case "smtp":
+		return &SMTPNotifier{cfg: cfg}, nil
+	default:
+		return nil, fmt.Errorf("pocketmon: unknown notifier type %q", cfg.Type)
+	}
+}
+
+func postJSON(url string, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", url, bytes.NewBuffer(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode > 399 {
+		return fmt.Errorf("notifier: %s returned %d", url, resp.StatusCode)
+	}
+	return nil
+}
+
+// SlackNotifier posts to a Slack incoming webhook.
+type SlackNotifier struct {
+	webhookURL string
+}
+
+func (n *SlackNotifier) Notify(a Alert) error {
+	return postJSON(n.webhookURL, map[string]string{"text": fmt.Sprintf("[%s] %s", a.Rule, a.Message)})
+}
+
+// DiscordNotifier posts to a Discord webhook.
+type DiscordNotifier struct {
+	webhookURL string
+}
+
+func (n *DiscordNotifier) Notify(a Alert) error {
+	return postJSON(n.webhookURL, map[string]string{"content": fmt.Sprintf("[%s] %s", a.Rule, a.Message)})
+}
+
+// PagerDutyNotifier triggers a PagerDuty Events v2 incident.
+type PagerDutyNotifier struct {
+	routingKey string
+}
+
+func (n *PagerDutyNotifier) Notify(a Alert) error {
+	return postJSON("https://events.pagerduty.com/v2/enqueue", map[string]interface{}{
+		"routing_key":  n.routingKey,
+		"event_action": "trigger",
+		"payload": map[string]string{
+			"summary":  fmt.Sprintf("[%s] %s", a.Rule, a.Message),
+			"source":   "pocketmon",
+			"severity": "critical",
+		},
+	})
+}
+
+// WebhookNotifier posts a generic JSON payload to an arbitrary URL.
+type WebhookNotifier struct {
+	url string
+}
+
+func (n *WebhookNotifier) Notify(a Alert) error {
+	return postJSON(n.url, map[string]string{"rule": a.Rule, "message": a.Message})
+}
+
+// SMTPNotifier emails the alert via a configured SMTP relay.
+type SMTPNotifier struct {
+	cfg NotifierConfig
+}
+
+func (n *SMTPNotifier) Notify(a Alert) error {
+	addr := fmt.Sprintf("%s:%d", n.cfg.SMTPHost, n.cfg.SMTPPort)
+
+	var auth smtp.Auth
+	if n.cfg.Username != "" {
+		auth = smtp.PlainAuth("", n.cfg.Username, n.cfg.Password, n.cfg.SMTPHost)
+	}
+
+	msg := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: pocketmon alert: %s\r\n\r\n%s\r\n",
+		n.cfg.From, n.cfg.To, a.Rule, a.Message)
+
+	return smtp.SendMail(addr, auth, n.cfg.From, []string{n.cfg.To}, []byte(msg))
+}
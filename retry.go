@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+const (
+	backoffBase = 5 * time.Second
+	backoffCap  = 5 * time.Minute
+
+	requestTimeout = 15 * time.Second
+)
+
+// retryableError wraps a delivery failure with an optional server-asked
+// Retry-After, so the flusher can honor 429s instead of guessing.
+type retryableError struct {
+	err        error
+	retryAfter time.Duration
+}
+
+func (e *retryableError) Error() string { return e.err.Error() }
+func (e *retryableError) Unwrap() error { return e.err }
+
+// isRetryable reports whether err is worth spooling and retrying later
+// (a network error, a 5xx, or a 429). Anything else — a bad API key, a
+// payload the server 400s on — will fail the same way every time, so it
+// must not be spooled or it would wedge the queue forever.
+func isRetryable(err error) bool {
+	var rerr *retryableError
+	return errors.As(err, &rerr)
+}
+
+// fullJitterBackoff implements AWS's "full jitter" backoff: a random
+// duration between 0 and min(cap, base*2^attempt).
+func fullJitterBackoff(attempt int) time.Duration {
+	if attempt < 0 {
+		attempt = 0
+	}
+	if attempt > 20 { // avoid overflowing the shift
+		attempt = 20
+	}
+
+	exp := backoffBase * time.Duration(uint64(1)<<uint(attempt))
+	if exp <= 0 || exp > backoffCap {
+		exp = backoffCap
+	}
+
+	return time.Duration(rand.Int63n(int64(exp) + 1))
+}
+
+// runFlusher retries spooled Stats in the background, oldest first,
+// stopping at the first retryable failure in a pass and backing off before the
+// next attempt. It exits when ctx is done.
+func runFlusher(ctx context.Context, sp *Spool, node, key string) {
+	attempt := 0
+	for {
+		wait := fullJitterBackoff(attempt)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+
+		drained, err := flushSpool(ctx, sp, node, key)
+		if err != nil {
+			attempt++
+			continue
+		}
+		if drained == 0 {
+			attempt = 0
+			continue
+		}
+		attempt = 0
+	}
+}
+
+// flushSpool delivers every pending payload, oldest first. A retryable
+// failure (outage, 429, 5xx) stops the pass so order is preserved and
+// the caller can back off; a non-retryable failure (bad API key, a
+// payload the server 400s on) would fail forever, so that entry is
+// dropped and the pass continues instead of wedging everything behind it.
+func flushSpool(ctx context.Context, sp *Spool, node, key string) (int, error) {
+	paths, err := sp.Pending()
+	if err != nil {
+		return 0, err
+	}
+
+	drained := 0
+	for _, path := range paths {
+		s, err := sp.Load(path)
+		if err != nil {
+			log.Println("spool: dropping unreadable entry", path, err)
+			sp.Remove(path)
+			continue
+		}
+
+		if err := sendStats(ctx, node, key, s); err != nil {
+			var rerr *retryableError
+			if !errors.As(err, &rerr) {
+				log.Println("spool: dropping permanently-failing entry", path, err)
+				sp.Remove(path)
+				continue
+			}
+
+			if rerr.retryAfter > 0 {
+				select {
+				case <-ctx.Done():
+				case <-time.After(rerr.retryAfter):
+				}
+			}
+			return drained, err
+		}
+
+		sp.Remove(path)
+		drained++
+	}
+
+	return drained, nil
+}
+
+func newHTTPClient() *http.Client {
+	return &http.Client{
+		Timeout: requestTimeout,
+		Transport: &http.Transport{
+			MaxIdleConns:        20,
+			MaxIdleConnsPerHost: 5,
+			IdleConnTimeout:     90 * time.Second,
+		},
+	}
+}
@@ -0,0 +1,37 @@
+package main
+
+import "testing"
+
+func TestFullJitterBackoff_StaysWithinBounds(t *testing.T) {
+	cases := []struct {
+		attempt int
+		max     int64 // exclusive upper bound in nanoseconds
+	}{
+		{attempt: -1, max: int64(backoffBase) + 1}, // negative attempts clamp to 0
+		{attempt: 0, max: int64(backoffBase) + 1},
+		{attempt: 1, max: int64(2*backoffBase) + 1},
+		{attempt: 2, max: int64(4*backoffBase) + 1},
+		{attempt: 10, max: int64(backoffCap) + 1},  // big enough to have hit the cap
+		{attempt: 100, max: int64(backoffCap) + 1}, // must not overflow the shift
+	}
+
+	for _, c := range cases {
+		for i := 0; i < 50; i++ {
+			d := fullJitterBackoff(c.attempt)
+			if d < 0 {
+				t.Fatalf("attempt %d: backoff went negative: %v", c.attempt, d)
+			}
+			if int64(d) >= c.max {
+				t.Fatalf("attempt %d: backoff %v exceeded expected bound %v", c.attempt, d, c.max)
+			}
+		}
+	}
+}
+
+func TestFullJitterBackoff_NeverExceedsCap(t *testing.T) {
+	for i := 0; i < 50; i++ {
+		if d := fullJitterBackoff(30); d > backoffCap {
+			t.Fatalf("backoff %v exceeded cap %v", d, backoffCap)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Spool is a bounded on-disk queue of Stats payloads that failed to
+// deliver. Each entry is a single JSON file named so that sorting
+// filenames sorts by enqueue order; the flusher in retry.go drains it.
+type Spool struct {
+	dir      string
+	maxItems int
+
+	mu      sync.Mutex
+	counter int
+}
+
+// NewSpool creates (if needed) dir and returns a Spool bounded to
+// maxItems pending entries; Enqueue drops the oldest entry once full.
+func NewSpool(dir string, maxItems int) (*Spool, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &Spool{dir: dir, maxItems: maxItems}, nil
+}
+
+func (sp *Spool) Enqueue(s Stats) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+
+	sp.mu.Lock()
+	sp.counter++
+	name := fmt.Sprintf("%020d-%04d.json", time.Now().UnixNano(), sp.counter%10000)
+	sp.mu.Unlock()
+
+	path := filepath.Join(sp.dir, name)
+	if err := ioutil.WriteFile(path, data, 0o644); err != nil {
+		return err
+	}
+
+	return sp.evictOverflow()
+}
+
+// Pending returns the spooled payload file paths, oldest first.
+func (sp *Spool) Pending() ([]string, error) {
+	entries, err := ioutil.ReadDir(sp.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, e := range entries {
+		if !e.IsDir() && filepath.Ext(e.Name()) == ".json" {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names)
+
+	paths := make([]string, len(names))
+	for i, n := range names {
+		paths[i] = filepath.Join(sp.dir, n)
+	}
+	return paths, nil
+}
+
+func (sp *Spool) Load(path string) (Stats, error) {
+	var s Stats
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return s, err
+	}
+	err = json.Unmarshal(data, &s)
+	return s, err
+}
+
+func (sp *Spool) Remove(path string) error {
+	return os.Remove(path)
+}
+
+// evictOverflow drops the oldest spooled entries once the queue grows
+// past maxItems, so a long outage can't fill the disk.
+func (sp *Spool) evictOverflow() error {
+	if sp.maxItems <= 0 {
+		return nil
+	}
+
+	paths, err := sp.Pending()
+	if err != nil {
+		return err
+	}
+	if len(paths) <= sp.maxItems {
+		return nil
+	}
+
+	for _, p := range paths[:len(paths)-sp.maxItems] {
+		if err := sp.Remove(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
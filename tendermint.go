@@ -0,0 +1,218 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultMissedBlocksWindow = 100
+
+	// livenessWalkBudget bounds how long missedBlocksLastN and detectFork
+	// may spend making one-RPC-per-block round trips in a single
+	// collectStats tick. Without it, a degraded-but-not-dead RPC (the
+	// exact condition this feature exists to catch) can make each
+	// round-trip take up to requestTimeout, and at the default
+	// MissedBlocksWindow that adds up to tens of minutes — stalling
+	// sendStats and alert evaluation for the whole process exactly when
+	// the node is unhealthy. Past the budget, the walk returns whatever
+	// it's accumulated so far instead of blocking the collection loop.
+	livenessWalkBudget = 20 * time.Second
+)
+
+// tendermintGet is shared by the pocket and cosmos adapters, which both
+// sit on top of a Tendermint RPC, to fetch validator-health signals
+// beyond what /status alone provides.
+func tendermintGet(rpcURL, path string, cfg AdapterConfig) (map[string]interface{}, error) {
+	req, err := newAuthedRequest("GET", rpcURL+path, nil, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Body.Close()
+
+	var result map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func tendermintPeerCount(rpcURL string, cfg AdapterConfig) (int, error) {
+	resp, err := tendermintGet(rpcURL, "/net_info", cfg)
+	if err != nil {
+		return 0, err
+	}
+	result, ok := resp["result"].(map[string]interface{})
+	if !ok {
+		return 0, fmt.Errorf("invalid data from /net_info call")
+	}
+	nPeers, ok := result["n_peers"].(string)
+	if !ok {
+		return 0, fmt.Errorf("missing n_peers in /net_info response")
+	}
+	return strconv.Atoi(nPeers)
+}
+
+// blockLagSeconds compares a Tendermint block timestamp to wall clock,
+// for detecting a node that's stopped producing blocks on time.
+func blockLagSeconds(latestBlockTime string) (float64, error) {
+	t, err := time.Parse(time.RFC3339Nano, latestBlockTime)
+	if err != nil {
+		return 0, err
+	}
+	return time.Since(t).Seconds(), nil
+}
+
+func blockSignerAddresses(rpcURL string, cfg AdapterConfig, height int64) ([]string, error) {
+	resp, err := tendermintGet(rpcURL, fmt.Sprintf("/block?height=%d", height), cfg)
+	if err != nil {
+		return nil, err
+	}
+	result, ok := resp["result"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid data from /block call")
+	}
+	blockData, ok := result["block"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("missing block in /block response")
+	}
+	lastCommit, ok := blockData["last_commit"].(map[string]interface{})
+	if !ok {
+		return nil, nil
+	}
+	sigs, ok := lastCommit["signatures"].([]interface{})
+	if !ok {
+		return nil, nil
+	}
+
+	var addrs []string
+	for _, sig := range sigs {
+		sm, ok := sig.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if addr, _ := sm["validator_address"].(string); addr != "" {
+			addrs = append(addrs, addr)
+		}
+	}
+	return addrs, nil
+}
+
+// missedBlocksLastN walks the n blocks ending at height and counts how
+// many don't carry a signature from address. The walk stops early, best
+// effort, once deadline passes, so one degraded RPC can't make a single
+// collection tick run for the full n round-trips.
+func missedBlocksLastN(rpcURL string, cfg AdapterConfig, address string, height int64, n int, deadline time.Time) (int, error) {
+	if address == "" || n <= 0 {
+		return 0, nil
+	}
+
+	missed := 0
+	for h := height; h > height-int64(n) && h > 0; h-- {
+		if !deadline.IsZero() && time.Now().After(deadline) {
+			break
+		}
+		addrs, err := blockSignerAddresses(rpcURL, cfg, h)
+		if err != nil {
+			return missed, err
+		}
+
+		signed := false
+		for _, a := range addrs {
+			if a == address {
+				signed = true
+				break
+			}
+		}
+		if !signed {
+			missed++
+		}
+	}
+	return missed, nil
+}
+
+func blockHashAt(rpcURL string, cfg AdapterConfig, height int64) (string, error) {
+	resp, err := tendermintGet(rpcURL, fmt.Sprintf("/block?height=%d", height), cfg)
+	if err != nil {
+		return "", err
+	}
+	result, ok := resp["result"].(map[string]interface{})
+	if !ok {
+		return "", fmt.Errorf("invalid data from /block call")
+	}
+	blockID, ok := result["block_id"].(map[string]interface{})
+	if !ok {
+		return "", fmt.Errorf("missing block_id in /block response")
+	}
+	hash, _ := blockID["hash"].(string)
+	return hash, nil
+}
+
+// detectFork cross-checks the local block hash at height against each
+// reference RPC endpoint's hash for the same height. Like
+// missedBlocksLastN, it gives up on remaining references, best effort,
+// once deadline passes.
+func detectFork(localRPC string, cfg AdapterConfig, height int64, refs []string, deadline time.Time) (bool, error) {
+	if len(refs) == 0 {
+		return false, nil
+	}
+
+	localHash, err := blockHashAt(localRPC, cfg, height)
+	if err != nil {
+		return false, err
+	}
+
+	for _, ref := range refs {
+		if !deadline.IsZero() && time.Now().After(deadline) {
+			break
+		}
+		refHash, err := blockHashAt(ref, cfg, height)
+		if err != nil {
+			continue
+		}
+		if refHash != "" && refHash != localHash {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// collectTendermintLivenessStats fills in the validator-health signals
+// beyond Height — peer count, block-time drift, missed blocks, and
+// forks — shared by the pocket and cosmos adapters, which both sit on
+// top of a Tendermint RPC. These are best-effort: a failure just leaves
+// the corresponding field at its zero value.
+func collectTendermintLivenessStats(rpcURL string, cfg AdapterConfig, s *NodeStats) {
+	if peers, err := tendermintPeerCount(rpcURL, cfg); err == nil {
+		s.PeerCount = peers
+	}
+
+	if lag, err := blockLagSeconds(s.LatestBlockTime); err == nil {
+		s.BlockLagSeconds = lag
+	}
+
+	// Both walks below are best-effort and share a single deadline, so
+	// together they get one livenessWalkBudget rather than one each; time
+	// a slow RPC uses during the missed-blocks walk is taken from what is
+	// left for the fork check.
+	deadline := time.Now().Add(livenessWalkBudget)
+
+	n := cfg.MissedBlocksWindow
+	if n == 0 {
+		n = defaultMissedBlocksWindow
+	}
+	if missed, err := missedBlocksLastN(rpcURL, cfg, s.Address, s.Height, n, deadline); err == nil {
+		s.MissedBlocksLastN = missed
+	}
+
+	if fork, err := detectFork(rpcURL, cfg, s.Height, cfg.ForkReferenceRPCs, deadline); err == nil {
+		s.ForkDetected = fork
+	}
+}
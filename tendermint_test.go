@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+const testValidatorAddr = "AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA"
+
+// blockServer serves /block?height=N, signing every height in signedHeights
+// with testValidatorAddr and leaving the rest unsigned.
+func blockServer(t *testing.T, signedHeights map[int64]bool) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		height, _ := strconv.ParseInt(r.URL.Query().Get("height"), 10, 64)
+
+		sigs := "[]"
+		if signedHeights[height] {
+			sigs = fmt.Sprintf(`[{"validator_address":"%s"}]`, testValidatorAddr)
+		}
+
+		fmt.Fprintf(w, `{"result":{"block":{"last_commit":{"signatures":%s}},"block_id":{"hash":"hash-%d"}}}`, sigs, height)
+	}))
+}
+
+func TestMissedBlocksLastN_CountsUnsignedBlocks(t *testing.T) {
+	signed := map[int64]bool{10: true, 9: false, 8: true, 7: true, 6: false}
+	srv := blockServer(t, signed)
+	defer srv.Close()
+
+	missed, err := missedBlocksLastN(srv.URL, AdapterConfig{}, testValidatorAddr, 10, 5, time.Time{})
+	if err != nil {
+		t.Fatalf("missedBlocksLastN returned error: %v", err)
+	}
+	if missed != 2 {
+		t.Fatalf("expected 2 missed blocks, got %d", missed)
+	}
+}
+
+func TestMissedBlocksLastN_NoAddressOrWindowIsANoOp(t *testing.T) {
+	if missed, err := missedBlocksLastN("http://unused", AdapterConfig{}, "", 10, 5, time.Time{}); err != nil || missed != 0 {
+		t.Fatalf("expected (0, nil) with no address, got (%d, %v)", missed, err)
+	}
+	if missed, err := missedBlocksLastN("http://unused", AdapterConfig{}, testValidatorAddr, 10, 0, time.Time{}); err != nil || missed != 0 {
+		t.Fatalf("expected (0, nil) with n=0, got (%d, %v)", missed, err)
+	}
+}
+
+func TestMissedBlocksLastN_StopsEarlyOncePastDeadline(t *testing.T) {
+	signed := map[int64]bool{10: false, 9: false, 8: false, 7: false, 6: false}
+	srv := blockServer(t, signed)
+	defer srv.Close()
+
+	// A deadline that's already passed should stop the walk before it
+	// makes a single request, leaving the count at its starting value
+	// instead of blocking on every one of the n round-trips.
+	missed, err := missedBlocksLastN(srv.URL, AdapterConfig{}, testValidatorAddr, 10, 5, time.Now().Add(-time.Minute))
+	if err != nil {
+		t.Fatalf("missedBlocksLastN returned error: %v", err)
+	}
+	if missed != 0 {
+		t.Fatalf("expected the walk to bail before counting anything, got %d", missed)
+	}
+}
+
+func TestDetectFork_TrueWhenReferenceHashDiffers(t *testing.T) {
+	local := blockServer(t, nil)
+	defer local.Close()
+
+	// A reference node reporting a different hash at the same height.
+	ref := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"result":{"block":{"last_commit":{"signatures":[]}},"block_id":{"hash":"a-different-hash"}}}`)
+	}))
+	defer ref.Close()
+
+	forked, err := detectFork(local.URL, AdapterConfig{}, 10, []string{ref.URL}, time.Time{})
+	if err != nil {
+		t.Fatalf("detectFork returned error: %v", err)
+	}
+	if !forked {
+		t.Fatalf("expected a fork to be detected")
+	}
+}
+
+func TestDetectFork_FalseWhenHashesMatch(t *testing.T) {
+	local := blockServer(t, nil)
+	defer local.Close()
+
+	forked, err := detectFork(local.URL, AdapterConfig{}, 10, []string{local.URL}, time.Time{})
+	if err != nil {
+		t.Fatalf("detectFork returned error: %v", err)
+	}
+	if forked {
+		t.Fatalf("expected no fork when the reference is the same node")
+	}
+}
+
+func TestDetectFork_NoReferencesIsANoOp(t *testing.T) {
+	forked, err := detectFork("http://unused", AdapterConfig{}, 10, nil, time.Time{})
+	if err != nil || forked {
+		t.Fatalf("expected (false, nil) with no reference RPCs, got (%v, %v)", forked, err)
+	}
+}